@@ -0,0 +1,556 @@
+package board
+
+import (
+	"fmt"
+	"log"
+
+	"chess/movegen"
+)
+
+type coords struct {
+	x, y int
+}
+
+func (c coords) String() string {
+	if c.x < 0 || 7 < c.x || c.y < 0 || 7 < c.y {
+		panic(fmt.Sprintf("Illegal coordinates (%d, %d)", c.x, c.y))
+	}
+	return fmt.Sprintf("%c%d", "ABCDEFGH"[c.x], c.y+1)
+}
+
+func (c *coords) Scan(state fmt.ScanState, verb rune) error {
+	rx, _, _ := state.ReadRune()
+	ry, _, _ := state.ReadRune()
+	if rx < 'A' || 'H' < rx || ry < '1' || '8' < ry {
+		return fmt.Errorf("Illegal chess coordinates: <%c, %c>", rx, ry)
+	}
+	c.x = int(rx - 'A')
+	c.y = int(ry - '1')
+	return nil
+}
+
+type pieceBareType int
+
+const (
+	PAWN pieceBareType = iota
+	KNIGHT
+	BISHOP
+	ROOK
+	QUEEN
+	KING
+)
+
+type pieceColor int
+
+const (
+	BLACK pieceColor = iota
+	WHITE
+)
+
+type pieceType struct {
+	t pieceBareType
+	c pieceColor
+}
+
+func (pt pieceType) String() string {
+	switch pt.c {
+	case WHITE:
+		switch pt.t {
+		case PAWN:
+			return "♙"
+		case KNIGHT:
+			return "♘"
+		case BISHOP:
+			return "♗"
+		case ROOK:
+			return "♖"
+		case QUEEN:
+			return "♕"
+		case KING:
+			return "♔"
+		}
+		break
+	case BLACK:
+		switch pt.t {
+		case PAWN:
+			return "♟"
+		case KNIGHT:
+			return "♞"
+		case BISHOP:
+			return "♝"
+		case ROOK:
+			return "♜"
+		case QUEEN:
+			return "♛"
+		case KING:
+			return "♚"
+		}
+		break
+	}
+	panic("Illegal piece type")
+}
+
+// Operations on pieces
+type pop interface{}
+
+type popGetCoords chan<- coords
+
+type popSetCoords coords
+
+// Die. Close this channel when operation acknowledged (for sync)
+type popKill chan<- bool
+
+// Subscribe to moves by request all coordinates updates be sent down here.
+// Send nil channel to cancel.
+type popMoveCallback chan<- coords
+
+type popSetType pieceType
+
+type popGetType chan<- pieceType
+
+type piece chan<- pop
+
+// Operations on a chess board
+type bop interface{}
+
+// Place a new piece on the board
+type bopNewPiece struct {
+	coords
+	ctrl chan<- pop
+}
+
+type bopMovePiece struct {
+	from, to coords
+	// promotion is the piece the moving pawn becomes when it reaches the
+	// back rank. The zero value (PAWN) is not a legal promotion target and
+	// is taken to mean "unspecified", which defaults to QUEEN.
+	promotion pieceBareType
+	// err, if non-nil, receives a single error and is closed when the move
+	// is illegal, instead of runBoard panicking.
+	err chan<- error
+}
+
+type bopGetAllPieces chan<- piece
+
+type bopDelPiece piece
+
+// bopLegalMoves asks for every square the piece on `from` may legally move
+// to; the results are sent down out, which is then closed.
+type bopLegalMoves struct {
+	from coords
+	out  chan<- coords
+}
+
+// bopLoadFEN replaces the entire board with the position described by fen,
+// reporting a parse error (or nil, on success) on done.
+type bopLoadFEN struct {
+	fen  string
+	done chan<- error
+}
+
+// bopExportFEN sends the FEN string for the current position down the
+// channel, which is then closed.
+type bopExportFEN chan<- string
+
+// bopGetBitboard asks for the occupancy bitboard (one bit per occupied
+// square, LSB = A1) of one piece kind/color pair.
+type bopGetBitboard struct {
+	kind  pieceBareType
+	color pieceColor
+	out   chan<- uint64
+}
+
+// bopGetOccupancy asks for the combined occupancy bitboard of every piece
+// of one color.
+type bopGetOccupancy struct {
+	color pieceColor
+	out   chan<- uint64
+}
+
+type board chan<- bop
+
+func toSquare(c coords) movegen.Square { return movegen.Square{X: c.x, Y: c.y} }
+
+func toCoords(s movegen.Square) coords { return coords{s.X, s.Y} }
+
+func toMGPiece(pt pieceType) movegen.Piece {
+	return movegen.Piece{Kind: movegen.Kind(pt.t), Color: movegen.Color(pt.c)}
+}
+
+// reportIllegal delivers err on t.err if the caller asked to be told about
+// illegal moves that way, and panics otherwise (preserving the board's
+// original behavior for callers that don't pass one).
+func reportIllegal(t bopMovePiece, err error) {
+	if t.err != nil {
+		t.err <- err
+		close(t.err)
+		return
+	}
+	panic(err.Error())
+}
+
+func killPiece(p piece) {
+	donec := make(chan bool)
+	p <- popKill(donec)
+	<-donec
+}
+
+// newBoardPiece spawns a piece actor directly, without routing it through
+// the board channel. Seeding the board in bulk (bopLoadFEN) needs this:
+// going via bopNewPiece from inside runBoard's own loop would deadlock.
+func newBoardPiece(x, y int, pt pieceType) piece {
+	c := make(chan pop)
+	go spawnPiece(c)
+	c <- popSetType(pt)
+	c <- popSetCoords{x, y}
+	return c
+}
+
+// Control operations are read from the control channel.
+func spawnPiece(c <-chan pop) {
+	var x, y int
+	var movechan chan<- coords
+	defer func() {
+		if movechan != nil {
+			close(movechan)
+		}
+	}()
+	var pt pieceType
+	for op := range c {
+		switch t := op.(type) {
+		case popSetCoords:
+			x = t.x
+			y = t.y
+			if movechan != nil {
+				movechan <- coords(t)
+			}
+		case popGetCoords:
+			t <- coords{x, y}
+			close(t)
+		case popKill:
+			close(t)
+			return
+		case popMoveCallback:
+			movechan = t
+		case popSetType:
+			pt = pieceType(t)
+		case popGetType:
+			t <- pt
+			close(t)
+		default:
+			panic(fmt.Sprintf("Illegal operation: %v", op))
+		}
+	}
+}
+
+func addPiece(x, y int, pt pieceType, b board) piece {
+	// Start a piece
+	c := make(chan pop)
+	go spawnPiece(c)
+	// Make it a pawn
+	c <- popSetType(pt)
+	// Move it to the desired coordinates
+	c <- popSetCoords{x, y}
+	b <- bopNewPiece{coords: coords{x, y}, ctrl: c}
+	// piece will push updates to coordinates down this channel
+	coordUpdates := make(chan coords)
+	c <- popMoveCallback(coordUpdates)
+	// Translate those updates to a message that includes the control channel
+	go func() {
+		for xy := range coordUpdates {
+			b <- bopNewPiece{xy, c}
+		}
+	}()
+	return c
+}
+
+func addPawn(x int, color pieceColor, b board) piece {
+	var y int
+	if color == WHITE {
+		y = 1
+	} else {
+		y = 6
+	}
+	return addPiece(x, y, pieceType{PAWN, color}, b)
+}
+
+func baseline(color pieceColor) int {
+	if color == WHITE {
+		return 0
+	}
+	return 7
+}
+
+func addKnight(x int, color pieceColor, b board) piece {
+	return addPiece(x, baseline(color), pieceType{KNIGHT, color}, b)
+}
+
+func addBishop(x int, color pieceColor, b board) piece {
+	return addPiece(x, baseline(color), pieceType{BISHOP, color}, b)
+}
+
+func addRook(x int, color pieceColor, b board) piece {
+	return addPiece(x, baseline(color), pieceType{ROOK, color}, b)
+}
+
+func addQueen(color pieceColor, b board) piece {
+	return addPiece(3, baseline(color), pieceType{QUEEN, color}, b)
+}
+
+func addKing(color pieceColor, b board) piece {
+	return addPiece(4, baseline(color), pieceType{KING, color}, b)
+}
+
+// Run a board management unit.  Closes the done channel when all updates have
+// been consumed and the input channel is closed (for sync).
+func runBoard(c <-chan bop, done chan<- bool) {
+	pieces := map[coords]piece{}
+	pos := movegen.NewBoard()
+	pos.Castling = movegen.CastlingRights{WhiteKingside: true, WhiteQueenside: true, BlackKingside: true, BlackQueenside: true}
+	pos.RecomputeHash()
+	halfmove, fullmove := 0, 1
+	history := []boardSnapshot{}
+	repetitions := map[uint64]int{}
+	var subscribers []chan<- MoveEvent
+	for o := range c {
+		switch t := o.(type) {
+		case bopNewPiece:
+			if _, exists := pieces[t.coords]; exists {
+				panic(fmt.Sprintf("A piece already exists on %s", t.coords))
+			}
+			pieces[t.coords] = t.ctrl
+			pc := make(chan pieceType)
+			t.ctrl <- popGetType(pc)
+			pt := <-pc
+			log.Printf("New piece: %s on %s", pt, t.coords)
+			pos.Place(toSquare(t.coords), toMGPiece(pt))
+			break
+		case bopMovePiece:
+			p, exists := pieces[t.from]
+			if !exists {
+				reportIllegal(t, fmt.Errorf("no piece at %s", t.from))
+				break
+			}
+			from, to := toSquare(t.from), toSquare(t.to)
+			promotion := t.promotion
+			if promotion == PAWN {
+				promotion = QUEEN
+			}
+			var mv *movegen.Move
+			for _, m := range movegen.LegalMoves(pos, from) {
+				if m.To == to && (!m.Promote || m.Promotion == movegen.Kind(promotion)) {
+					mc := m
+					mv = &mc
+					break
+				}
+			}
+			if mv == nil {
+				reportIllegal(t, fmt.Errorf("illegal move from %s to %s", t.from, t.to))
+				break
+			}
+			if len(history) == 0 {
+				// The position the board is sitting in hasn't been through
+				// a move yet -- either this is the game's starting
+				// position (initBoard never seeds repetitions the way
+				// bopLoadFEN does) or we've undone back to it. Count it as
+				// seen before recording the move that leaves it.
+				repetitions[pos.Hash()] = 1
+			}
+			history = append(history, boardSnapshot{pos: pos.Clone(), halfmove: halfmove, fullmove: fullmove})
+			beforeFEN := positionSnapshot(pos, halfmove, fullmove).FEN()
+			moverColor := pos.Squares[from].Color
+			pawnMove := pos.Squares[from].Kind == movegen.Pawn
+			capture := mv.EnPassant
+			if mv.EnPassant {
+				capSq := coords{t.to.x, t.from.y}
+				if cp, captured := pieces[capSq]; captured {
+					killPiece(cp)
+					delete(pieces, capSq)
+				}
+			} else if cp, captured := pieces[t.to]; captured {
+				killPiece(cp)
+				capture = true
+			}
+			delete(pieces, t.from)
+			pieces[t.to] = p
+			if mv.Castle {
+				rookFrom, rookTo := toCoords(mv.CastleRookFrom), toCoords(mv.CastleRookTo)
+				if rp, ok := pieces[rookFrom]; ok {
+					delete(pieces, rookFrom)
+					pieces[rookTo] = rp
+				}
+			}
+			pos.Apply(*mv)
+			if pawnMove || capture {
+				halfmove = 0
+			} else {
+				halfmove++
+			}
+			if moverColor == movegen.Black {
+				fullmove++
+			}
+			repetitions[pos.Hash()]++
+			pc := make(chan pieceType)
+			p <- popGetType(pc)
+			log.Printf("Move: %s from %s to %s", <-pc, t.from, t.to)
+			if len(subscribers) > 0 {
+				status := movegen.PositionStatus(pos)
+				event := MoveEvent{
+					Move:      *mv,
+					Color:     moverColor,
+					BeforeFEN: beforeFEN,
+					Check:     status == movegen.Check || status == movegen.Checkmate,
+					Checkmate: status == movegen.Checkmate,
+				}
+				for _, sub := range subscribers {
+					sub <- event
+				}
+			}
+			if t.err != nil {
+				close(t.err)
+			}
+		case bopSubscribe:
+			subscribers = append(subscribers, t)
+		case bopLegalMoves:
+			for _, m := range movegen.LegalMoves(pos, toSquare(t.from)) {
+				t.out <- toCoords(m.To)
+			}
+			close(t.out)
+		case bopLoadFEN:
+			newPos, err := ParseFEN(t.fen)
+			if err != nil {
+				t.done <- err
+				close(t.done)
+				break
+			}
+			for _, p := range pieces {
+				killPiece(p)
+			}
+			pieces = map[coords]piece{}
+			pos = movegen.NewBoard()
+			pos.Castling = newPos.Castling
+			pos.SideToMove = movegen.Color(newPos.SideToMove)
+			if newPos.EnPassant != nil {
+				ep := toSquare(*newPos.EnPassant)
+				pos.EnPassant = &ep
+			}
+			halfmove, fullmove = newPos.Halfmove, newPos.Fullmove
+			for sq, pt := range newPos.Board {
+				pieces[sq] = newBoardPiece(sq.x, sq.y, pt)
+				pos.Place(toSquare(sq), toMGPiece(pt))
+			}
+			pos.RecomputeHash()
+			history = history[:0]
+			repetitions = map[uint64]int{pos.Hash(): 1}
+			close(t.done)
+		case bopExportFEN:
+			t <- positionSnapshot(pos, halfmove, fullmove).FEN()
+			close(t)
+		case bopGetBitboard:
+			t.out <- pos.Bitboard(movegen.Kind(t.kind), movegen.Color(t.color))
+			close(t.out)
+		case bopGetOccupancy:
+			t.out <- pos.Occupancy(movegen.Color(t.color))
+			close(t.out)
+		case bopUndoLastMove:
+			if len(history) == 0 {
+				t <- fmt.Errorf("no move to undo")
+				close(t)
+				break
+			}
+			snap := history[len(history)-1]
+			history = history[:len(history)-1]
+			repetitions[pos.Hash()]--
+			if repetitions[pos.Hash()] <= 0 {
+				delete(repetitions, pos.Hash())
+			}
+			for _, p := range pieces {
+				killPiece(p)
+			}
+			pieces = map[coords]piece{}
+			for sq, pt := range snap.pos.Squares {
+				co := toCoords(sq)
+				pieces[co] = newBoardPiece(co.x, co.y, pieceType{t: pieceBareType(pt.Kind), c: pieceColor(pt.Color)})
+			}
+			pos = snap.pos
+			halfmove, fullmove = snap.halfmove, snap.fullmove
+			close(t)
+		case bopGetHash:
+			t <- pos.Hash()
+			close(t)
+		case bopIsDraw:
+			t <- drawReason(pos, halfmove, repetitions)
+			close(t)
+		case bopGetAllPieces:
+			for _, p := range pieces {
+				t <- p
+			}
+			close(t)
+			break
+		case bopDelPiece:
+			donec := make(chan bool)
+			cc := make(chan coords)
+			t <- popGetCoords(cc)
+			coords := <-cc
+			t <- popKill(donec)
+			<-donec
+			delete(pieces, coords)
+			pos.Remove(toSquare(coords))
+			log.Printf("Deleted piece from %s", coords)
+			break
+		default:
+			panic(fmt.Sprintf("Illegal board operation: %v", o))
+		}
+	}
+	close(done)
+}
+
+func initBoard1p(b board, color pieceColor) {
+	addPawn(0, color, b)
+	addPawn(1, color, b)
+	addPawn(2, color, b)
+	addPawn(3, color, b)
+	addPawn(4, color, b)
+	addPawn(5, color, b)
+	addPawn(6, color, b)
+	addPawn(7, color, b)
+	addRook(0, color, b)
+	addKnight(1, color, b)
+	addBishop(2, color, b)
+	addQueen(color, b)
+	addKing(color, b)
+	addBishop(5, color, b)
+	addKnight(6, color, b)
+	addRook(7, color, b)
+}
+
+// Initialize an empty chess board by putting pieces in the right places
+func initBoard(b board) {
+	initBoard1p(b, WHITE)
+	initBoard1p(b, BLACK)
+}
+
+func clearBoard(b board) {
+	piecesc := make(chan piece)
+	b <- bopGetAllPieces(piecesc)
+	// Two-step to avoid dead-lock
+	pieces := []piece{}
+	for p := range piecesc {
+		pieces = append(pieces, p)
+	}
+	for _, p := range pieces {
+		b <- bopDelPiece(p)
+	}
+	return
+}
+
+// Parse human-readable coordinates into a move operation
+func parseMoveOp(from, to string) (op bopMovePiece, err error) {
+	_, err = fmt.Sscan(from, &op.from)
+	if err != nil {
+		return
+	}
+	_, err = fmt.Sscan(to, &op.to)
+	return
+}
+
@@ -0,0 +1,230 @@
+package board
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"chess/movegen"
+)
+
+// Position is a self-contained snapshot of board state: where the pieces
+// are, whose move it is, castling and en-passant rights, and the two
+// clocks needed for the fifty-move rule and move numbering. It mirrors the
+// structure of a CPG Position object, so the board actor can be seeded
+// from or dumped to one instead of only ever starting from the hardcoded
+// initial setup in initBoard.
+type Position struct {
+	Board      map[coords]pieceType
+	SideToMove pieceColor
+	Castling   movegen.CastlingRights
+	EnPassant  *coords
+	Halfmove   int
+	Fullmove   int
+}
+
+// ParseFEN parses standard Forsyth-Edwards Notation into a Position.
+func ParseFEN(fen string) (*Position, error) {
+	fields := strings.Fields(fen)
+	if len(fields) != 6 {
+		return nil, fmt.Errorf("invalid FEN %q: expected 6 space-separated fields, got %d", fen, len(fields))
+	}
+
+	pos := &Position{Board: map[coords]pieceType{}}
+
+	ranks := strings.Split(fields[0], "/")
+	if len(ranks) != 8 {
+		return nil, fmt.Errorf("invalid FEN %q: expected 8 ranks, got %d", fen, len(ranks))
+	}
+	for i, rank := range ranks {
+		y := 7 - i
+		x := 0
+		for _, r := range rank {
+			if '1' <= r && r <= '8' {
+				x += int(r - '0')
+				if x > 8 {
+					return nil, fmt.Errorf("invalid FEN %q: rank %q overflows the board", fen, rank)
+				}
+				continue
+			}
+			pt, err := pieceFromFENRune(r)
+			if err != nil {
+				return nil, fmt.Errorf("invalid FEN %q: %v", fen, err)
+			}
+			if x > 7 {
+				return nil, fmt.Errorf("invalid FEN %q: rank %q overflows the board", fen, rank)
+			}
+			pos.Board[coords{x, y}] = pt
+			x++
+		}
+		if x != 8 {
+			return nil, fmt.Errorf("invalid FEN %q: rank %q has %d squares, want 8", fen, rank, x)
+		}
+	}
+
+	switch fields[1] {
+	case "w":
+		pos.SideToMove = WHITE
+	case "b":
+		pos.SideToMove = BLACK
+	default:
+		return nil, fmt.Errorf("invalid FEN %q: side to move must be \"w\" or \"b\", got %q", fen, fields[1])
+	}
+
+	if fields[2] != "-" {
+		for _, r := range fields[2] {
+			switch r {
+			case 'K':
+				pos.Castling.WhiteKingside = true
+			case 'Q':
+				pos.Castling.WhiteQueenside = true
+			case 'k':
+				pos.Castling.BlackKingside = true
+			case 'q':
+				pos.Castling.BlackQueenside = true
+			default:
+				return nil, fmt.Errorf("invalid FEN %q: unknown castling flag %q", fen, r)
+			}
+		}
+	}
+
+	if fields[3] != "-" {
+		var ep coords
+		if _, err := fmt.Sscan(strings.ToUpper(fields[3]), &ep); err != nil {
+			return nil, fmt.Errorf("invalid FEN %q: bad en passant square %q: %v", fen, fields[3], err)
+		}
+		pos.EnPassant = &ep
+	}
+
+	if _, err := fmt.Sscan(fields[4], &pos.Halfmove); err != nil {
+		return nil, fmt.Errorf("invalid FEN %q: bad halfmove clock %q: %v", fen, fields[4], err)
+	}
+	if _, err := fmt.Sscan(fields[5], &pos.Fullmove); err != nil {
+		return nil, fmt.Errorf("invalid FEN %q: bad fullmove number %q: %v", fen, fields[5], err)
+	}
+
+	return pos, nil
+}
+
+// FEN renders pos as standard Forsyth-Edwards Notation.
+func (pos *Position) FEN() string {
+	ranks := make([]string, 0, 8)
+	for y := 7; y >= 0; y-- {
+		rank, empty := "", 0
+		for x := 0; x <= 7; x++ {
+			pt, ok := pos.Board[coords{x, y}]
+			if !ok {
+				empty++
+				continue
+			}
+			if empty > 0 {
+				rank += strconv.Itoa(empty)
+				empty = 0
+			}
+			rank += fenRune(pt)
+		}
+		if empty > 0 {
+			rank += strconv.Itoa(empty)
+		}
+		ranks = append(ranks, rank)
+	}
+
+	side := "w"
+	if pos.SideToMove == BLACK {
+		side = "b"
+	}
+
+	castling := ""
+	if pos.Castling.WhiteKingside {
+		castling += "K"
+	}
+	if pos.Castling.WhiteQueenside {
+		castling += "Q"
+	}
+	if pos.Castling.BlackKingside {
+		castling += "k"
+	}
+	if pos.Castling.BlackQueenside {
+		castling += "q"
+	}
+	if castling == "" {
+		castling = "-"
+	}
+
+	ep := "-"
+	if pos.EnPassant != nil {
+		ep = strings.ToLower(pos.EnPassant.String())
+	}
+
+	return fmt.Sprintf("%s %s %s %s %d %d", strings.Join(ranks, "/"), side, castling, ep, pos.Halfmove, pos.Fullmove)
+}
+
+func pieceFromFENRune(r rune) (pieceType, error) {
+	color := WHITE
+	lower := r
+	if unicode.IsLower(r) {
+		color = BLACK
+	} else {
+		lower = unicode.ToLower(r)
+	}
+	var t pieceBareType
+	switch lower {
+	case 'p':
+		t = PAWN
+	case 'n':
+		t = KNIGHT
+	case 'b':
+		t = BISHOP
+	case 'r':
+		t = ROOK
+	case 'q':
+		t = QUEEN
+	case 'k':
+		t = KING
+	default:
+		return pieceType{}, fmt.Errorf("unknown piece letter %q", r)
+	}
+	return pieceType{t, color}, nil
+}
+
+func fenRune(pt pieceType) string {
+	var r string
+	switch pt.t {
+	case PAWN:
+		r = "p"
+	case KNIGHT:
+		r = "n"
+	case BISHOP:
+		r = "b"
+	case ROOK:
+		r = "r"
+	case QUEEN:
+		r = "q"
+	case KING:
+		r = "k"
+	}
+	if pt.c == WHITE {
+		return strings.ToUpper(r)
+	}
+	return r
+}
+
+// positionSnapshot captures the board actor's live state as a Position.
+func positionSnapshot(pos *movegen.Board, halfmove, fullmove int) *Position {
+	snap := &Position{
+		Board:      map[coords]pieceType{},
+		SideToMove: pieceColor(pos.SideToMove),
+		Castling:   pos.Castling,
+		Halfmove:   halfmove,
+		Fullmove:   fullmove,
+	}
+	for sq, p := range pos.Squares {
+		snap.Board[toCoords(sq)] = pieceType{t: pieceBareType(p.Kind), c: pieceColor(p.Color)}
+	}
+	if pos.EnPassant != nil {
+		ep := toCoords(*pos.EnPassant)
+		snap.EnPassant = &ep
+	}
+	return snap
+}
@@ -0,0 +1,16 @@
+package board
+
+import "testing"
+
+func TestParseFENRejectsRanksOfTheWrongWidth(t *testing.T) {
+	cases := []string{
+		"44444444/8/8/8/8/8/8/8 w KQkq - 0 1", // digits alone sum to 32 on rank 8
+		"7/8/8/8/8/8/8/8 w KQkq - 0 1",        // rank 8 is one square short
+		"9/8/8/8/8/8/8/8 w KQkq - 0 1",        // rank 8 holds a digit past 8
+	}
+	for _, fen := range cases {
+		if _, err := ParseFEN(fen); err == nil {
+			t.Errorf("ParseFEN(%q) = nil error, want a rank-width error", fen)
+		}
+	}
+}
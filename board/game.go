@@ -0,0 +1,191 @@
+package board
+
+import (
+	"fmt"
+	"strings"
+
+	"chess/movegen"
+)
+
+// Game is the high-level façade over the actor-based board: it owns the
+// board's command channel and exposes the primitives a real engine or UI
+// needs -- legal moves, make/unmake, FEN import/export, hashing and draw
+// detection -- without exposing the channel plumbing underneath.
+type Game struct {
+	boardc chan bop
+	done   chan bool
+}
+
+// NewGame starts a board actor set up with the standard initial position.
+func NewGame() *Game {
+	g := newEmptyGame()
+	initBoard(g.boardc)
+	return g
+}
+
+// NewEmptyGame starts a board actor with no pieces on it, ready for
+// LoadFEN.
+func NewEmptyGame() *Game {
+	return newEmptyGame()
+}
+
+func newEmptyGame() *Game {
+	boardc := make(chan bop)
+	done := make(chan bool)
+	go runBoard(boardc, done)
+	return &Game{boardc: boardc, done: done}
+}
+
+// Close tears down the board actor, releasing every piece on it.
+func (g *Game) Close() {
+	clearBoard(g.boardc)
+	close(g.boardc)
+	<-g.done
+}
+
+// Move plays a move given in human-readable coordinates (e.g. "E2", "E4").
+// promotion is the letter of the piece a pawn promotes to ('Q','R','B','N');
+// pass 0 if the move isn't a promotion or QUEEN should be assumed. It
+// returns the illegal-move error, if any, instead of panicking.
+func (g *Game) Move(from, to string, promotion rune) error {
+	op, err := parseMoveOp(from, to)
+	if err != nil {
+		return err
+	}
+	if pt, ok := promotionFromRune(promotion); ok {
+		op.promotion = pt
+	}
+	errc := make(chan error, 1)
+	op.err = errc
+	g.boardc <- op
+	return <-errc
+}
+
+func promotionFromRune(r rune) (pieceBareType, bool) {
+	switch r {
+	case 'Q', 'q':
+		return QUEEN, true
+	case 'R', 'r':
+		return ROOK, true
+	case 'B', 'b':
+		return BISHOP, true
+	case 'N', 'n':
+		return KNIGHT, true
+	default:
+		return PAWN, false
+	}
+}
+
+// LegalMoves returns the destination squares (e.g. "E4") the piece on
+// `from` may legally move to.
+func (g *Game) LegalMoves(from string) ([]string, error) {
+	var fc coords
+	if _, err := fmt.Sscan(from, &fc); err != nil {
+		return nil, err
+	}
+	out := make(chan coords)
+	g.boardc <- bopLegalMoves{from: fc, out: out}
+	var dests []string
+	for c := range out {
+		dests = append(dests, c.String())
+	}
+	return dests, nil
+}
+
+// AllLegalMoves returns every legal move available to the side to move, in
+// long algebraic notation (e.g. "E2E4", "E7E8q").
+func (g *Game) AllLegalMoves() []string {
+	mb, err := FENBoard(g.FEN())
+	if err != nil {
+		return nil
+	}
+	var moves []string
+	for _, m := range movegen.AllLegalMoves(mb, mb.SideToMove) {
+		moves = append(moves, moveToUCI(m))
+	}
+	return moves
+}
+
+// FENBoard parses fen into the equivalent *movegen.Board, for callers (like
+// package pgn) that need movegen's primitives directly without going
+// through board's unexported coords/pieceType types.
+func FENBoard(fen string) (*movegen.Board, error) {
+	pos, err := ParseFEN(fen)
+	if err != nil {
+		return nil, err
+	}
+	mb := movegen.NewBoard()
+	mb.SideToMove = movegen.Color(pos.SideToMove)
+	mb.Castling = pos.Castling
+	if pos.EnPassant != nil {
+		ep := toSquare(*pos.EnPassant)
+		mb.EnPassant = &ep
+	}
+	for c, pt := range pos.Board {
+		mb.Place(toSquare(c), toMGPiece(pt))
+	}
+	return mb, nil
+}
+
+// Subscribe registers events to receive every completed move from this
+// point on. See MoveEvent for what each one carries.
+func (g *Game) Subscribe(events chan<- MoveEvent) {
+	g.boardc <- bopSubscribe(events)
+}
+
+func moveToUCI(m movegen.Move) string {
+	s := toCoords(m.From).String() + toCoords(m.To).String()
+	if m.Promote {
+		s += strings.ToLower(promotionLetter(m.Promotion))
+	}
+	return s
+}
+
+func promotionLetter(k movegen.Kind) string {
+	switch k {
+	case movegen.Queen:
+		return "Q"
+	case movegen.Rook:
+		return "R"
+	case movegen.Bishop:
+		return "B"
+	case movegen.Knight:
+		return "N"
+	}
+	return ""
+}
+
+// LoadFEN replaces the position with the one described by fen.
+func (g *Game) LoadFEN(fen string) error {
+	done := make(chan error, 1)
+	g.boardc <- bopLoadFEN{fen: fen, done: done}
+	return <-done
+}
+
+// FEN exports the current position as Forsyth-Edwards Notation.
+func (g *Game) FEN() string {
+	out := make(chan string)
+	g.boardc <- bopExportFEN(out)
+	return <-out
+}
+
+// Undo reverts the last move played.
+func (g *Game) Undo() error {
+	errc := make(chan error, 1)
+	g.boardc <- bopUndoLastMove(errc)
+	return <-errc
+}
+
+// Hash returns the Zobrist key of the current position.
+func (g *Game) Hash() uint64 {
+	out := make(chan uint64)
+	g.boardc <- bopGetHash(out)
+	return <-out
+}
+
+// IsDraw reports whether the current position is drawn, and why.
+func (g *Game) IsDraw() DrawReason {
+	out := make(chan DrawReason)
+	g.boardc <- bopIsDraw(out)
+	return <-out
+}
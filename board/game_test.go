@@ -0,0 +1,142 @@
+package board
+
+import "testing"
+
+func TestFENRoundTrip(t *testing.T) {
+	const fen = "r1bqkbnr/pppp1ppp/2n5/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R b KQkq - 2 3"
+	g := NewEmptyGame()
+	defer g.Close()
+
+	if err := g.LoadFEN(fen); err != nil {
+		t.Fatalf("LoadFEN(%q): %v", fen, err)
+	}
+	if got := g.FEN(); got != fen {
+		t.Errorf("FEN() = %q, want %q", got, fen)
+	}
+}
+
+func TestHFileMoveIsLegal(t *testing.T) {
+	g := NewGame()
+	defer g.Close()
+
+	if err := g.Move("H2", "H4", 0); err != nil {
+		t.Fatalf("Move(H2, H4) = %v, want nil", err)
+	}
+}
+
+func TestFoolsMateReportsCheckmate(t *testing.T) {
+	g := NewGame()
+	defer g.Close()
+
+	events := make(chan MoveEvent, 4)
+	g.Subscribe(events)
+
+	moves := [][2]string{{"F2", "F3"}, {"E7", "E5"}, {"G2", "G4"}, {"D8", "H4"}}
+	var last MoveEvent
+	for _, m := range moves {
+		if err := g.Move(m[0], m[1], 0); err != nil {
+			t.Fatalf("Move(%s, %s): %v", m[0], m[1], err)
+		}
+		last = <-events
+	}
+
+	if !last.Checkmate {
+		t.Errorf("last move's MoveEvent.Checkmate = false, want true (fool's mate)")
+	}
+}
+
+func TestKingsideCastling(t *testing.T) {
+	g := NewEmptyGame()
+	defer g.Close()
+
+	const fen = "r3k2r/8/8/8/8/8/8/R3K2R w KQkq - 0 1"
+	if err := g.LoadFEN(fen); err != nil {
+		t.Fatalf("LoadFEN(%q): %v", fen, err)
+	}
+	if err := g.Move("E1", "G1", 0); err != nil {
+		t.Fatalf("Move(E1, G1): %v", err)
+	}
+
+	const want = "r3k2r/8/8/8/8/8/8/R4RK1 b kq - 1 1"
+	if got := g.FEN(); got != want {
+		t.Errorf("FEN() after O-O = %q, want %q", got, want)
+	}
+}
+
+func TestEnPassantCapture(t *testing.T) {
+	g := NewEmptyGame()
+	defer g.Close()
+
+	const fen = "4k3/8/8/4pP2/8/8/8/4K3 w - e6 0 1"
+	if err := g.LoadFEN(fen); err != nil {
+		t.Fatalf("LoadFEN(%q): %v", fen, err)
+	}
+	if err := g.Move("F5", "E6", 0); err != nil {
+		t.Fatalf("Move(F5, E6) en passant: %v", err)
+	}
+
+	const want = "4k3/8/4P3/8/8/8/8/4K3 b - - 0 1"
+	if got := g.FEN(); got != want {
+		t.Errorf("FEN() after en passant = %q, want %q", got, want)
+	}
+}
+
+func TestPawnPromotion(t *testing.T) {
+	g := NewEmptyGame()
+	defer g.Close()
+
+	const fen = "6k1/4P3/8/8/8/8/8/4K3 w - - 0 1"
+	if err := g.LoadFEN(fen); err != nil {
+		t.Fatalf("LoadFEN(%q): %v", fen, err)
+	}
+	if err := g.Move("E7", "E8", 'Q'); err != nil {
+		t.Fatalf("Move(E7, E8, Q): %v", err)
+	}
+
+	const want = "4Q1k1/8/8/8/8/8/8/4K3 b - - 0 1"
+	if got := g.FEN(); got != want {
+		t.Errorf("FEN() after promotion = %q, want %q", got, want)
+	}
+	if hash := g.Hash(); hash == 0 {
+		t.Errorf("Hash() = 0, want a real Zobrist key after promotion")
+	}
+}
+
+func TestUndoRestoresPosition(t *testing.T) {
+	g := NewGame()
+	defer g.Close()
+
+	before := g.FEN()
+	if err := g.Move("E2", "E4", 0); err != nil {
+		t.Fatalf("Move: %v", err)
+	}
+	if err := g.Undo(); err != nil {
+		t.Fatalf("Undo: %v", err)
+	}
+	if got := g.FEN(); got != before {
+		t.Errorf("FEN() after undo = %q, want %q", got, before)
+	}
+}
+
+func TestThreefoldRepetitionFromStartingPosition(t *testing.T) {
+	g := NewGame()
+	defer g.Close()
+
+	// Shuffle a knight out and back twice, transposing into the starting
+	// position three times in total (the start, plus two round trips).
+	moves := [][2]string{
+		{"G1", "F3"}, {"G8", "F6"},
+		{"F3", "G1"}, {"F6", "G8"},
+		{"G1", "F3"}, {"G8", "F6"},
+		{"F3", "G1"}, {"F6", "G8"},
+	}
+	for _, m := range moves {
+		if err := g.Move(m[0], m[1], 0); err != nil {
+			t.Fatalf("Move(%s, %s): %v", m[0], m[1], err)
+		}
+	}
+
+	if reason := g.IsDraw(); reason != ThreefoldRepetition {
+		t.Errorf("IsDraw() = %v, want %v", reason, ThreefoldRepetition)
+	}
+}
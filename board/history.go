@@ -0,0 +1,93 @@
+package board
+
+import "chess/movegen"
+
+// boardSnapshot is everything runBoard needs to restore the position to
+// how it was immediately before a move, for bopUndoLastMove.
+type boardSnapshot struct {
+	pos      *movegen.Board
+	halfmove int
+	fullmove int
+}
+
+// DrawReason is why a position is drawn, as reported by bopIsDraw.
+type DrawReason int
+
+const (
+	NotDraw DrawReason = iota
+	ThreefoldRepetition
+	FiftyMoveRule
+	InsufficientMaterial
+	StalemateDraw
+)
+
+func (r DrawReason) String() string {
+	switch r {
+	case NotDraw:
+		return "not a draw"
+	case ThreefoldRepetition:
+		return "threefold repetition"
+	case FiftyMoveRule:
+		return "fifty-move rule"
+	case InsufficientMaterial:
+		return "insufficient material"
+	case StalemateDraw:
+		return "stalemate"
+	}
+	return "unknown draw reason"
+}
+
+// bopUndoLastMove reverts the last bopMovePiece (including captures and
+// promotions), reporting an error down the channel if there is no move to
+// undo.
+type bopUndoLastMove chan<- error
+
+// bopGetHash asks for the Zobrist key of the current position.
+type bopGetHash chan<- uint64
+
+// bopIsDraw asks whether the current position is drawn, and why.
+type bopIsDraw chan<- DrawReason
+
+// drawReason evaluates every drawing condition runBoard tracks.
+func drawReason(pos *movegen.Board, halfmove int, repetitions map[uint64]int) DrawReason {
+	if repetitions[pos.Hash()] >= 3 {
+		return ThreefoldRepetition
+	}
+	if halfmove >= 100 {
+		return FiftyMoveRule
+	}
+	if insufficientMaterial(pos) {
+		return InsufficientMaterial
+	}
+	if movegen.PositionStatus(pos) == movegen.Stalemate {
+		return StalemateDraw
+	}
+	return NotDraw
+}
+
+// insufficientMaterial reports whether neither side has enough material
+// left to deliver checkmate: K v K, K+minor v K, or opposite-but-actually
+// same-colored-bishop K+B v K+B.
+func insufficientMaterial(pos *movegen.Board) bool {
+	var minors int
+	var bishops []movegen.Square
+	for sq, p := range pos.Squares {
+		switch p.Kind {
+		case movegen.Pawn, movegen.Rook, movegen.Queen:
+			return false
+		case movegen.Knight:
+			minors++
+		case movegen.Bishop:
+			minors++
+			bishops = append(bishops, sq)
+		}
+	}
+	switch {
+	case minors == 0, minors == 1:
+		return true
+	case minors == 2 && len(bishops) == 2:
+		return (bishops[0].X+bishops[0].Y)%2 == (bishops[1].X+bishops[1].Y)%2
+	default:
+		return false
+	}
+}
@@ -0,0 +1,21 @@
+package board
+
+import "chess/movegen"
+
+// MoveEvent describes one completed move, carrying the FEN the position was
+// in just before the move was played. That's enough context for an
+// observer (like package pgn) to reconstruct SAN -- including
+// disambiguation against other pieces of the same kind -- without needing
+// access to board's unexported coords/pieceType internals.
+type MoveEvent struct {
+	Move      movegen.Move
+	Color     movegen.Color
+	BeforeFEN string
+	Check     bool
+	Checkmate bool
+}
+
+// bopSubscribe registers a channel to receive every MoveEvent as moves
+// complete. Subscriptions last for the life of the board; there is no
+// unsubscribe, mirroring how a piece only ever gains one move callback.
+type bopSubscribe chan<- MoveEvent
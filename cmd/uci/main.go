@@ -0,0 +1,127 @@
+// Command uci speaks the Universal Chess Interface (UCI) protocol over
+// stdin/stdout, driving a board.Game: uci, isready, ucinewgame, position
+// [startpos|fen ...] moves ..., go (with wtime/btime/movetime/depth), stop
+// and quit. This makes the module usable with standard chess GUIs (Arena,
+// Cutechess, lichess-bot).
+//
+// The search behind "go" is deliberately minimal -- the first legal move
+// found -- since the point of this binary is to exercise the primitives
+// board.Game exposes (legal move list, make/unmake, FEN, side to move),
+// not to play well.
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"chess/board"
+)
+
+func main() {
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	g := board.NewEmptyGame()
+	defer g.Close()
+	resetToStartpos(g)
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "uci":
+			fmt.Println("id name chesstoy")
+			fmt.Println("id author jessethegame")
+			fmt.Println("uciok")
+		case "isready":
+			fmt.Println("readyok")
+		case "ucinewgame":
+			resetToStartpos(g)
+		case "position":
+			handlePosition(g, fields[1:])
+		case "go":
+			handleGo(fields[1:])
+			printBestMove(g)
+		case "stop":
+			printBestMove(g)
+		case "quit":
+			return
+		}
+	}
+}
+
+func resetToStartpos(g *board.Game) {
+	const startFEN = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"
+	if err := g.LoadFEN(startFEN); err != nil {
+		panic(err.Error())
+	}
+}
+
+func handlePosition(g *board.Game, args []string) {
+	if len(args) == 0 {
+		return
+	}
+	i := 1
+	switch args[0] {
+	case "startpos":
+		resetToStartpos(g)
+	case "fen":
+		if len(args) < 7 {
+			fmt.Fprintln(os.Stderr, "position fen: too few fields")
+			return
+		}
+		if err := g.LoadFEN(strings.Join(args[1:7], " ")); err != nil {
+			fmt.Fprintf(os.Stderr, "position fen: %v\n", err)
+			return
+		}
+		i = 7
+	default:
+		return
+	}
+	if i < len(args) && args[i] == "moves" {
+		for _, mv := range args[i+1:] {
+			from, to, promotion := parseUCIMove(mv)
+			if err := g.Move(from, to, promotion); err != nil {
+				fmt.Fprintf(os.Stderr, "illegal move %s: %v\n", mv, err)
+				return
+			}
+		}
+	}
+}
+
+// parseUCIMove splits long algebraic notation like "e2e4" or "e7e8q" into
+// board.Game.Move's from/to/promotion arguments.
+func parseUCIMove(mv string) (from, to string, promotion rune) {
+	from = strings.ToUpper(mv[0:2])
+	to = strings.ToUpper(mv[2:4])
+	if len(mv) > 4 {
+		promotion = rune(mv[4])
+	}
+	return
+}
+
+// handleGo parses the subset of "go" parameters a real engine would use for
+// time management; this minimal search ignores all of them, but parsing
+// keeps the protocol loop from choking on a GUI that always sends them.
+func handleGo(args []string) {
+	for i := 0; i+1 < len(args); i += 2 {
+		switch args[i] {
+		case "wtime", "btime", "movetime", "depth":
+			strconv.Atoi(args[i+1])
+		}
+	}
+}
+
+func printBestMove(g *board.Game) {
+	moves := g.AllLegalMoves()
+	if len(moves) == 0 {
+		fmt.Println("bestmove 0000")
+		return
+	}
+	fmt.Println("bestmove " + strings.ToLower(moves[0]))
+}
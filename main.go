@@ -0,0 +1,12 @@
+package main
+
+import "chess/board"
+
+func main() {
+	g := board.NewGame()
+	// Open with a white pawn
+	if err := g.Move("D2", "D4", 0); err != nil {
+		panic(err.Error())
+	}
+	g.Close()
+}
@@ -0,0 +1,564 @@
+// Package movegen computes legal chess moves for a position: pseudo-legal
+// generation per piece kind, filtering out moves that leave the mover's own
+// king in check, and check/checkmate/stalemate detection. It mirrors the
+// piece/color/coordinate vocabulary used by the board actor in package board
+// (board/chess.go) closely enough that converting between the two is a
+// matter of casting the underlying ints.
+package movegen
+
+// Color is which side a piece belongs to or whose turn it is to move.
+type Color int
+
+const (
+	Black Color = iota
+	White
+)
+
+// Opponent returns the other color.
+func (c Color) Opponent() Color {
+	if c == White {
+		return Black
+	}
+	return White
+}
+
+// Kind is a piece's type, independent of color.
+type Kind int
+
+const (
+	Pawn Kind = iota
+	Knight
+	Bishop
+	Rook
+	Queen
+	King
+)
+
+// Square is a board coordinate, 0-7 in each axis.
+type Square struct {
+	X, Y int
+}
+
+func (s Square) onBoard() bool {
+	return 0 <= s.X && s.X <= 7 && 0 <= s.Y && s.Y <= 7
+}
+
+func (s Square) add(dx, dy int) Square {
+	return Square{s.X + dx, s.Y + dy}
+}
+
+// Piece is a piece kind plus its color.
+type Piece struct {
+	Kind  Kind
+	Color Color
+}
+
+// CastlingRights tracks, per side and per wing, whether castling is still
+// available. A right is lost for good the moment the king or that rook
+// moves (or the rook is captured); it is never regained.
+type CastlingRights struct {
+	WhiteKingside, WhiteQueenside bool
+	BlackKingside, BlackQueenside bool
+}
+
+// Board is a mailbox snapshot of the state needed to generate moves, kept
+// alongside twelve bitboards (one per kind x color, one bit per occupied
+// square, LSB = A1) that mirror the same pieces. The caller (runBoard, in
+// package board) keeps one of these in sync with the real board's pieces on
+// every placement, move and removal; Place and Remove update both
+// representations together so they can never drift apart.
+type Board struct {
+	Squares    map[Square]Piece
+	Bitboards  [2][6]uint64 // [Color][Kind]
+	SideToMove Color
+	Castling   CastlingRights
+	EnPassant  *Square // the square a pawn may capture onto en passant, if any
+
+	hash uint64 // Zobrist key, maintained incrementally -- see zobrist.go
+}
+
+// NewBoard returns an empty board with white to move and no special rights.
+func NewBoard() *Board {
+	return &Board{Squares: map[Square]Piece{}, SideToMove: White}
+}
+
+// Clone returns a deep-enough copy of b for simulating a move without
+// mutating the original.
+func (b *Board) Clone() *Board {
+	nb := &Board{
+		Squares:    make(map[Square]Piece, len(b.Squares)),
+		Bitboards:  b.Bitboards,
+		SideToMove: b.SideToMove,
+		Castling:   b.Castling,
+		hash:       b.hash,
+	}
+	for sq, p := range b.Squares {
+		nb.Squares[sq] = p
+	}
+	if b.EnPassant != nil {
+		ep := *b.EnPassant
+		nb.EnPassant = &ep
+	}
+	return nb
+}
+
+func sqBit(sq Square) uint64 { return uint64(1) << uint(sq.Y*8+sq.X) }
+
+func bitSquare(i int) Square { return Square{i % 8, i / 8} }
+
+// Place puts p on sq, updating both the mailbox and the bitboards (clearing
+// out whatever used to occupy sq first).
+func (b *Board) Place(sq Square, p Piece) {
+	if old, ok := b.Squares[sq]; ok {
+		b.Bitboards[old.Color][old.Kind] &^= sqBit(sq)
+		b.hash ^= zobristPiece[old.Color][old.Kind][sq.index()]
+	}
+	b.Squares[sq] = p
+	b.Bitboards[p.Color][p.Kind] |= sqBit(sq)
+	b.hash ^= zobristPiece[p.Color][p.Kind][sq.index()]
+}
+
+// Remove clears sq, updating both the mailbox and the bitboards. It is a
+// no-op if sq is already empty.
+func (b *Board) Remove(sq Square) {
+	if p, ok := b.Squares[sq]; ok {
+		b.Bitboards[p.Color][p.Kind] &^= sqBit(sq)
+		b.hash ^= zobristPiece[p.Color][p.Kind][sq.index()]
+		delete(b.Squares, sq)
+	}
+}
+
+// Bitboard returns the occupancy bitboard for one piece kind/color pair.
+func (b *Board) Bitboard(kind Kind, color Color) uint64 {
+	return b.Bitboards[color][kind]
+}
+
+// Occupancy returns the combined occupancy bitboard of every piece of
+// color.
+func (b *Board) Occupancy(color Color) uint64 {
+	var occ uint64
+	for k := Pawn; k <= King; k++ {
+		occ |= b.Bitboards[color][k]
+	}
+	return occ
+}
+
+// Move is a single pseudo-legal or legal move, carrying the extra
+// information needed to apply it: promotion kind, whether it is an
+// en-passant capture, and the rook leg of a castle.
+type Move struct {
+	From, To  Square
+	Promote   bool
+	Promotion Kind
+
+	EnPassant bool // To is empty; the captured pawn sits beside From
+
+	Castle                       bool
+	CastleRookFrom, CastleRookTo Square
+}
+
+// KingSquare returns the square of color's king, or ok=false if it has no
+// king on the board (only possible with a malformed position).
+func (b *Board) KingSquare(color Color) (Square, bool) {
+	for sq, p := range b.Squares {
+		if p.Kind == King && p.Color == color {
+			return sq, true
+		}
+	}
+	return Square{}, false
+}
+
+// Attacks reports whether sq is attacked by any piece of color `by`. Knight,
+// king and pawn attacks come from precomputed tables; sliding attacks walk
+// their rays against the combined occupancy bitboard. Either way this is an
+// O(1) bitboard lookup per candidate piece rather than a geometry check
+// against the mailbox.
+func (b *Board) Attacks(sq Square, by Color) bool {
+	target := sqBit(sq)
+	for from, p := range b.Squares {
+		if p.Color != by {
+			continue
+		}
+		if b.attacksBitboard(from, p)&target != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// attacksBitboard returns every square the piece p on `from` attacks.
+func (b *Board) attacksBitboard(from Square, p Piece) uint64 {
+	idx := from.index()
+	switch p.Kind {
+	case Pawn:
+		return pawnAttackTable[p.Color][idx]
+	case Knight:
+		return knightAttackTable[idx]
+	case King:
+		return kingAttackTable[idx]
+	case Bishop:
+		return slidingAttacks(from, b.Occupancy(White)|b.Occupancy(Black), bishopDirs)
+	case Rook:
+		return slidingAttacks(from, b.Occupancy(White)|b.Occupancy(Black), rookDirs)
+	case Queen:
+		occ := b.Occupancy(White) | b.Occupancy(Black)
+		return slidingAttacks(from, occ, bishopDirs) | slidingAttacks(from, occ, rookDirs)
+	}
+	return 0
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+var knightOffsets = [8][2]int{{1, 2}, {2, 1}, {2, -1}, {1, -2}, {-1, -2}, {-2, -1}, {-2, 1}, {-1, 2}}
+var kingOffsets = [8][2]int{{1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}, {0, -1}, {1, -1}}
+var bishopDirs = [4][2]int{{1, 1}, {1, -1}, {-1, 1}, {-1, -1}}
+var rookDirs = [4][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}}
+
+// knightAttackTable and kingAttackTable are precomputed per origin square
+// (index = y*8+x); pawnAttackTable is precomputed per color and square.
+// Sliding pieces have no fixed table -- their rays depend on occupancy, so
+// they are walked on demand by slidingAttacks.
+var knightAttackTable = buildJumpTable(knightOffsets[:])
+var kingAttackTable = buildJumpTable(kingOffsets[:])
+var pawnAttackTable = buildPawnAttackTable()
+
+func buildJumpTable(offsets [][2]int) [64]uint64 {
+	var table [64]uint64
+	for i := 0; i < 64; i++ {
+		from := bitSquare(i)
+		for _, o := range offsets {
+			if to := from.add(o[0], o[1]); to.onBoard() {
+				table[i] |= sqBit(to)
+			}
+		}
+	}
+	return table
+}
+
+func buildPawnAttackTable() [2][64]uint64 {
+	var table [2][64]uint64
+	dirs := [2]int{Black: -1, White: 1}
+	for color, dir := range dirs {
+		for i := 0; i < 64; i++ {
+			from := bitSquare(i)
+			for _, dx := range []int{-1, 1} {
+				if to := from.add(dx, dir); to.onBoard() {
+					table[color][i] |= sqBit(to)
+				}
+			}
+		}
+	}
+	return table
+}
+
+// slidingAttacks walks each direction in dirs from `from` until it falls off
+// the board or hits an occupied square (which blocks further squares on
+// that ray but is itself included, since it may be a capture).
+func slidingAttacks(from Square, occ uint64, dirs [4][2]int) uint64 {
+	var attacks uint64
+	for _, d := range dirs {
+		for to := from.add(d[0], d[1]); to.onBoard(); to = to.add(d[0], d[1]) {
+			bit := sqBit(to)
+			attacks |= bit
+			if occ&bit != 0 {
+				break
+			}
+		}
+	}
+	return attacks
+}
+
+// PseudoLegalMoves returns every move the piece on `from` could make
+// ignoring whether it leaves its own king in check.
+func PseudoLegalMoves(b *Board, from Square) []Move {
+	p, ok := b.Squares[from]
+	if !ok {
+		return nil
+	}
+	switch p.Kind {
+	case Pawn:
+		return pawnMoves(b, from, p)
+	case Knight:
+		return jumpMoves(b, from, p, knightOffsets[:])
+	case King:
+		moves := jumpMoves(b, from, p, kingOffsets[:])
+		return append(moves, castleMoves(b, from, p)...)
+	case Bishop:
+		return slideMoves(b, from, p, bishopDirs[:])
+	case Rook:
+		return slideMoves(b, from, p, rookDirs[:])
+	case Queen:
+		moves := slideMoves(b, from, p, bishopDirs[:])
+		return append(moves, slideMoves(b, from, p, rookDirs[:])...)
+	}
+	return nil
+}
+
+func jumpMoves(b *Board, from Square, p Piece, offsets [][2]int) []Move {
+	var moves []Move
+	for _, o := range offsets {
+		to := from.add(o[0], o[1])
+		if !to.onBoard() {
+			continue
+		}
+		if occ, ok := b.Squares[to]; ok && occ.Color == p.Color {
+			continue
+		}
+		moves = append(moves, Move{From: from, To: to})
+	}
+	return moves
+}
+
+func slideMoves(b *Board, from Square, p Piece, dirs [][2]int) []Move {
+	var moves []Move
+	for _, d := range dirs {
+		for to := from.add(d[0], d[1]); to.onBoard(); to = to.add(d[0], d[1]) {
+			occ, ok := b.Squares[to]
+			if !ok {
+				moves = append(moves, Move{From: from, To: to})
+				continue
+			}
+			if occ.Color != p.Color {
+				moves = append(moves, Move{From: from, To: to})
+			}
+			break
+		}
+	}
+	return moves
+}
+
+func pawnMoves(b *Board, from Square, p Piece) []Move {
+	var moves []Move
+	dir, startRank, lastRank := 1, 1, 7
+	if p.Color == Black {
+		dir, startRank, lastRank = -1, 6, 0
+	}
+	addWithPromotion := func(to Square) {
+		if to.Y == lastRank {
+			for _, k := range []Kind{Queen, Rook, Bishop, Knight} {
+				moves = append(moves, Move{From: from, To: to, Promote: true, Promotion: k})
+			}
+		} else {
+			moves = append(moves, Move{From: from, To: to})
+		}
+	}
+	one := from.add(0, dir)
+	if one.onBoard() {
+		if _, occ := b.Squares[one]; !occ {
+			addWithPromotion(one)
+			two := from.add(0, 2*dir)
+			if from.Y == startRank {
+				if _, occ := b.Squares[two]; !occ {
+					moves = append(moves, Move{From: from, To: two})
+				}
+			}
+		}
+	}
+	for _, dx := range []int{-1, 1} {
+		to := from.add(dx, dir)
+		if !to.onBoard() {
+			continue
+		}
+		if occ, ok := b.Squares[to]; ok && occ.Color != p.Color {
+			addWithPromotion(to)
+		} else if b.EnPassant != nil && *b.EnPassant == to {
+			moves = append(moves, Move{From: from, To: to, EnPassant: true})
+		}
+	}
+	return moves
+}
+
+func castleMoves(b *Board, from Square, p Piece) []Move {
+	var moves []Move
+	rank := 0
+	if p.Color == Black {
+		rank = 7
+	}
+	if from != (Square{4, rank}) {
+		return nil
+	}
+	opp := p.Color.Opponent()
+	if b.Attacks(from, opp) {
+		return nil
+	}
+	kingside, queenside := b.Castling.rights(p.Color)
+	if kingside && emptyAndSafe(b, rank, 5, 6, opp) {
+		if _, ok := b.Squares[Square{7, rank}]; ok {
+			moves = append(moves, Move{From: from, To: Square{6, rank}, Castle: true,
+				CastleRookFrom: Square{7, rank}, CastleRookTo: Square{5, rank}})
+		}
+	}
+	if queenside && emptyAndSafe(b, rank, 2, 3, opp) && isEmpty(b, Square{1, rank}) {
+		if _, ok := b.Squares[Square{0, rank}]; ok {
+			moves = append(moves, Move{From: from, To: Square{2, rank}, Castle: true,
+				CastleRookFrom: Square{0, rank}, CastleRookTo: Square{3, rank}})
+		}
+	}
+	return moves
+}
+
+func isEmpty(b *Board, sq Square) bool {
+	_, occ := b.Squares[sq]
+	return !occ
+}
+
+// emptyAndSafe reports whether every file in [fromX, toX] on rank is empty
+// and not attacked, i.e. the king may legally pass through or land there.
+func emptyAndSafe(b *Board, rank, fromX, toX int, opp Color) bool {
+	for x := fromX; x <= toX; x++ {
+		sq := Square{x, rank}
+		if !isEmpty(b, sq) {
+			return false
+		}
+		if b.Attacks(sq, opp) {
+			return false
+		}
+	}
+	return true
+}
+
+func (cr CastlingRights) rights(c Color) (kingside, queenside bool) {
+	if c == White {
+		return cr.WhiteKingside, cr.WhiteQueenside
+	}
+	return cr.BlackKingside, cr.BlackQueenside
+}
+
+// LegalMoves returns the subset of PseudoLegalMoves(b, from) that do not
+// leave the mover's own king in check.
+func LegalMoves(b *Board, from Square) []Move {
+	p, ok := b.Squares[from]
+	if !ok {
+		return nil
+	}
+	var legal []Move
+	for _, m := range PseudoLegalMoves(b, from) {
+		sim := b.Clone()
+		sim.Apply(m)
+		if king, ok := sim.KingSquare(p.Color); !ok || !sim.Attacks(king, p.Color.Opponent()) {
+			legal = append(legal, m)
+		}
+	}
+	return legal
+}
+
+// InCheck reports whether color's king currently sits on an attacked
+// square.
+func (b *Board) InCheck(color Color) bool {
+	king, ok := b.KingSquare(color)
+	return ok && b.Attacks(king, color.Opponent())
+}
+
+// AllLegalMoves returns every legal move available to color.
+func AllLegalMoves(b *Board, color Color) []Move {
+	var all []Move
+	for sq, p := range b.Squares {
+		if p.Color != color {
+			continue
+		}
+		all = append(all, LegalMoves(b, sq)...)
+	}
+	return all
+}
+
+// Status is the overall state of a position for the side to move.
+type Status int
+
+const (
+	Ongoing Status = iota
+	Check
+	Checkmate
+	Stalemate
+)
+
+// PositionStatus evaluates the game state for the side to move.
+func PositionStatus(b *Board) Status {
+	hasMove := len(AllLegalMoves(b, b.SideToMove)) > 0
+	inCheck := b.InCheck(b.SideToMove)
+	switch {
+	case !hasMove && inCheck:
+		return Checkmate
+	case !hasMove:
+		return Stalemate
+	case inCheck:
+		return Check
+	default:
+		return Ongoing
+	}
+}
+
+// Apply mutates b in place to reflect playing m, updating captures, the
+// rook leg of a castle, en-passant captures, promotion, castling rights,
+// the en-passant target square and side to move. It assumes m came from
+// PseudoLegalMoves(b, m.From) and does not revalidate it.
+func (b *Board) Apply(m Move) {
+	b.hash ^= zobristCastling[castlingIndex(b.Castling)]
+	if b.EnPassant != nil {
+		b.hash ^= zobristEPFile[b.EnPassant.X]
+	}
+
+	p := b.Squares[m.From]
+	b.Remove(m.From)
+
+	if m.EnPassant {
+		b.Remove(Square{m.To.X, m.From.Y})
+	}
+
+	if m.Promote {
+		p.Kind = m.Promotion
+	}
+	b.Place(m.To, p)
+
+	if m.Castle {
+		rook := b.Squares[m.CastleRookFrom]
+		b.Remove(m.CastleRookFrom)
+		b.Place(m.CastleRookTo, rook)
+	}
+
+	b.EnPassant = nil
+	if p.Kind == Pawn && abs(m.To.Y-m.From.Y) == 2 {
+		ep := Square{m.From.X, (m.From.Y + m.To.Y) / 2}
+		b.EnPassant = &ep
+	}
+
+	updateCastlingRights(&b.Castling, p, m)
+
+	b.hash ^= zobristCastling[castlingIndex(b.Castling)]
+	if b.EnPassant != nil {
+		b.hash ^= zobristEPFile[b.EnPassant.X]
+	}
+
+	b.SideToMove = b.SideToMove.Opponent()
+	b.hash ^= zobristBlackToMove
+}
+
+func updateCastlingRights(cr *CastlingRights, moved Piece, m Move) {
+	clearRookRight := func(sq Square) {
+		switch sq {
+		case Square{0, 0}:
+			cr.WhiteQueenside = false
+		case Square{7, 0}:
+			cr.WhiteKingside = false
+		case Square{0, 7}:
+			cr.BlackQueenside = false
+		case Square{7, 7}:
+			cr.BlackKingside = false
+		}
+	}
+	if moved.Kind == King {
+		if moved.Color == White {
+			cr.WhiteKingside, cr.WhiteQueenside = false, false
+		} else {
+			cr.BlackKingside, cr.BlackQueenside = false, false
+		}
+	}
+	if moved.Kind == Rook {
+		clearRookRight(m.From)
+	}
+	clearRookRight(m.To) // a captured rook also loses its side the right
+}
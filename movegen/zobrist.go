@@ -0,0 +1,77 @@
+package movegen
+
+import "math/rand"
+
+// zobristSeed is fixed so that a given position always hashes to the same
+// key across runs -- nothing depends on the keys being unpredictable, only
+// on them being stable and (with overwhelming probability) collision-free.
+const zobristSeed = 0xC0FFEE1951
+
+var (
+	zobristPiece       [2][6][64]uint64
+	zobristCastling    [16]uint64 // indexed by castlingIndex
+	zobristEPFile      [8]uint64
+	zobristBlackToMove uint64
+)
+
+func init() {
+	r := rand.New(rand.NewSource(zobristSeed))
+	for c := range zobristPiece {
+		for k := range zobristPiece[c] {
+			for sq := range zobristPiece[c][k] {
+				zobristPiece[c][k][sq] = r.Uint64()
+			}
+		}
+	}
+	for i := range zobristCastling {
+		zobristCastling[i] = r.Uint64()
+	}
+	for i := range zobristEPFile {
+		zobristEPFile[i] = r.Uint64()
+	}
+	zobristBlackToMove = r.Uint64()
+}
+
+func castlingIndex(cr CastlingRights) int {
+	idx := 0
+	if cr.WhiteKingside {
+		idx |= 1
+	}
+	if cr.WhiteQueenside {
+		idx |= 2
+	}
+	if cr.BlackKingside {
+		idx |= 4
+	}
+	if cr.BlackQueenside {
+		idx |= 8
+	}
+	return idx
+}
+
+func (s Square) index() int { return s.Y*8 + s.X }
+
+// Hash returns the Zobrist key for the exact position: piece placement,
+// castling rights, en-passant file and side to move. Place, Remove and
+// Apply keep it in sync incrementally; RecomputeHash rebuilds it from
+// scratch for callers that set Castling/EnPassant/SideToMove directly.
+func (b *Board) Hash() uint64 { return b.hash }
+
+// RecomputeHash rebuilds b.Hash() from scratch. Use it after setting
+// Castling, EnPassant or SideToMove directly (e.g. when loading a position
+// wholesale via repeated Place calls) -- those fields don't maintain the
+// hash themselves the way Place/Remove/Apply do.
+func (b *Board) RecomputeHash() {
+	var h uint64
+	for sq, p := range b.Squares {
+		h ^= zobristPiece[p.Color][p.Kind][sq.index()]
+	}
+	h ^= zobristCastling[castlingIndex(b.Castling)]
+	if b.EnPassant != nil {
+		h ^= zobristEPFile[b.EnPassant.X]
+	}
+	if b.SideToMove == Black {
+		h ^= zobristBlackToMove
+	}
+	b.hash = h
+}
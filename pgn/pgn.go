@@ -0,0 +1,415 @@
+// Package pgn records games played through a board.Game as Portable Game
+// Notation, and replays a PGN file back through one. Recording works by
+// subscribing to board.MoveEvent and converting each event to Standard
+// Algebraic Notation against the position it was played in; replaying works
+// by resolving each SAN token against the current legal move set and
+// feeding the result back through board.Game.Move.
+package pgn
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"chess/board"
+	"chess/movegen"
+)
+
+// Tags holds PGN's seven-tag roster.
+type Tags struct {
+	Event, Site, Date, Round, White, Black, Result string
+}
+
+// DefaultTags fills in the seven-tag roster with PGN's conventional
+// placeholders for an otherwise-unspecified game.
+func DefaultTags() Tags {
+	return Tags{
+		Event:  "?",
+		Site:   "?",
+		Date:   "????.??.??",
+		Round:  "?",
+		White:  "?",
+		Black:  "?",
+		Result: "*",
+	}
+}
+
+// Recorder watches a board.Game's moves and accumulates them in SAN, ready
+// to be rendered as PGN once the game ends.
+type Recorder struct {
+	tags  Tags
+	moves []string
+	done  chan struct{}
+}
+
+// NewRecorder creates a Recorder that will tag the game it records with
+// tags.
+func NewRecorder(tags Tags) *Recorder {
+	return &Recorder{tags: tags, done: make(chan struct{})}
+}
+
+// Watch consumes events until the channel is closed, appending the SAN of
+// each to the recorded move list. Callers typically run this in its own
+// goroutine alongside a subscribed board.Game, closing events once the
+// game is over and then calling Finish -- Finish blocks until Watch has
+// drained it, so the last move is never missed.
+func (r *Recorder) Watch(events <-chan board.MoveEvent) {
+	defer close(r.done)
+	for ev := range events {
+		san, err := SAN(ev)
+		if err != nil {
+			san = "???"
+		}
+		r.moves = append(r.moves, san)
+	}
+}
+
+// Finish waits for Watch to drain its events channel, then renders the
+// recorded game as PGN text, setting the Result tag to result (e.g. "1-0",
+// "0-1", "1/2-1/2", "*"). Callers must close the events channel passed to
+// Watch before calling Finish, or it blocks forever.
+func (r *Recorder) Finish(result string) string {
+	<-r.done
+	tags := r.tags
+	tags.Result = result
+	return Render(tags, r.moves)
+}
+
+// Render formats tags and a list of SAN moves as a complete PGN game.
+func Render(tags Tags, moves []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[Event %q]\n", tags.Event)
+	fmt.Fprintf(&b, "[Site %q]\n", tags.Site)
+	fmt.Fprintf(&b, "[Date %q]\n", tags.Date)
+	fmt.Fprintf(&b, "[Round %q]\n", tags.Round)
+	fmt.Fprintf(&b, "[White %q]\n", tags.White)
+	fmt.Fprintf(&b, "[Black %q]\n", tags.Black)
+	fmt.Fprintf(&b, "[Result %q]\n", tags.Result)
+	b.WriteString("\n")
+
+	var movetext strings.Builder
+	for i, san := range moves {
+		if i%2 == 0 {
+			if i > 0 {
+				movetext.WriteString(" ")
+			}
+			fmt.Fprintf(&movetext, "%d. %s", i/2+1, san)
+		} else {
+			fmt.Fprintf(&movetext, " %s", san)
+		}
+	}
+	b.WriteString(movetext.String())
+	if movetext.Len() > 0 {
+		b.WriteString(" ")
+	}
+	b.WriteString(tags.Result)
+	b.WriteString("\n")
+	return b.String()
+}
+
+// SAN renders ev's move in Standard Algebraic Notation, disambiguating
+// against any other piece of the same kind that could also have reached
+// the destination.
+func SAN(ev board.MoveEvent) (string, error) {
+	mb, err := board.FENBoard(ev.BeforeFEN)
+	if err != nil {
+		return "", err
+	}
+	m := ev.Move
+	piece, ok := mb.Squares[m.From]
+	if !ok {
+		return "", fmt.Errorf("pgn: no piece at %s in %s", squareString(m.From), ev.BeforeFEN)
+	}
+
+	if m.Castle {
+		san := "O-O"
+		if m.CastleRookTo.X < 4 {
+			san = "O-O-O"
+		}
+		return appendCheck(san, ev), nil
+	}
+
+	_, captured := mb.Squares[m.To]
+	capture := captured || m.EnPassant
+
+	var san string
+	if piece.Kind == movegen.Pawn {
+		if capture {
+			san = fileLetter(m.From.X) + "x"
+		}
+		san += squareString(m.To)
+		if m.Promote {
+			san += "=" + pieceLetter(m.Promotion)
+		}
+		return appendCheck(san, ev), nil
+	}
+
+	san = pieceLetter(piece.Kind) + disambiguate(mb, piece, m)
+	if capture {
+		san += "x"
+	}
+	san += squareString(m.To)
+	return appendCheck(san, ev), nil
+}
+
+// disambiguate returns the file, rank, or both qualifying m.From when other
+// pieces of the same kind and color could also legally move to m.To.
+func disambiguate(mb *movegen.Board, piece movegen.Piece, m movegen.Move) string {
+	var sameFile, sameRank, other bool
+	for _, alt := range movegen.AllLegalMoves(mb, piece.Color) {
+		if alt.From == m.From || alt.To != m.To {
+			continue
+		}
+		altPiece, ok := mb.Squares[alt.From]
+		if !ok || altPiece.Kind != piece.Kind {
+			continue
+		}
+		other = true
+		if alt.From.X == m.From.X {
+			sameFile = true
+		}
+		if alt.From.Y == m.From.Y {
+			sameRank = true
+		}
+	}
+	switch {
+	case !other:
+		return ""
+	case !sameFile:
+		return fileLetter(m.From.X)
+	case !sameRank:
+		return rankDigit(m.From.Y)
+	default:
+		return squareString(m.From)
+	}
+}
+
+func appendCheck(san string, ev board.MoveEvent) string {
+	switch {
+	case ev.Checkmate:
+		return san + "#"
+	case ev.Check:
+		return san + "+"
+	default:
+		return san
+	}
+}
+
+func fileLetter(x int) string { return string("abcdefgh"[x]) }
+func rankDigit(y int) string  { return strconv.Itoa(y + 1) }
+
+func squareString(sq movegen.Square) string { return fileLetter(sq.X) + rankDigit(sq.Y) }
+
+func pieceLetter(k movegen.Kind) string {
+	switch k {
+	case movegen.Knight:
+		return "N"
+	case movegen.Bishop:
+		return "B"
+	case movegen.Rook:
+		return "R"
+	case movegen.Queen:
+		return "Q"
+	case movegen.King:
+		return "K"
+	default:
+		return ""
+	}
+}
+
+// PGNReader replays SAN move text through a board.Game, resolving each
+// token against the game's current legal moves.
+type PGNReader struct {
+	g *board.Game
+}
+
+// NewPGNReader creates a PGNReader that plays moves into g.
+func NewPGNReader(g *board.Game) *PGNReader {
+	return &PGNReader{g: g}
+}
+
+// ReplayAll parses pgnText's movetext and plays every move into the reader's
+// game in order, stopping at the first illegal or unresolvable move.
+func (r *PGNReader) ReplayAll(pgnText string) error {
+	for _, san := range movetext(pgnText) {
+		if err := r.replayOne(san); err != nil {
+			return fmt.Errorf("pgn: replaying %q: %w", san, err)
+		}
+	}
+	return nil
+}
+
+func (r *PGNReader) replayOne(san string) error {
+	mb, err := board.FENBoard(r.g.FEN())
+	if err != nil {
+		return err
+	}
+	m, err := resolveSAN(mb, mb.SideToMove, san)
+	if err != nil {
+		return err
+	}
+	promotion := rune(0)
+	if m.Promote {
+		promotion = []rune(strings.ToLower(pieceLetter(m.Promotion)))[0]
+	}
+	// board.Game.Move takes squares in the uppercase form coords.Scan
+	// expects ("E2"), not SAN/UCI's lowercase ("e2").
+	from := strings.ToUpper(squareString(m.From))
+	to := strings.ToUpper(squareString(m.To))
+	return r.g.Move(from, to, promotion)
+}
+
+// movetext strips tag-pair lines and move numbers/results from pgnText,
+// returning just the SAN tokens in order.
+func movetext(pgnText string) []string {
+	var tokens []string
+	for _, line := range strings.Split(pgnText, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "[") {
+			continue
+		}
+		for _, f := range strings.Fields(line) {
+			if isMoveNumber(f) || isResult(f) {
+				continue
+			}
+			tokens = append(tokens, f)
+		}
+	}
+	return tokens
+}
+
+// isMoveNumber reports whether f is a movetext move-number marker like "1."
+// or "12...".
+func isMoveNumber(f string) bool {
+	digits := strings.TrimRight(f, ".")
+	if digits == f || digits == "" {
+		return false
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isResult(f string) bool {
+	switch f {
+	case "1-0", "0-1", "1/2-1/2", "*":
+		return true
+	default:
+		return false
+	}
+}
+
+// resolveSAN matches san against color's current legal moves in mb.
+func resolveSAN(mb *movegen.Board, color movegen.Color, san string) (movegen.Move, error) {
+	san = strings.TrimSuffix(strings.TrimSuffix(san, "+"), "#")
+	if san == "" {
+		return movegen.Move{}, fmt.Errorf("empty SAN token")
+	}
+
+	if san == "O-O" || san == "O-O-O" {
+		for _, m := range movegen.AllLegalMoves(mb, color) {
+			if !m.Castle {
+				continue
+			}
+			kingside := m.CastleRookTo.X > 4
+			if (san == "O-O") == kingside {
+				return m, nil
+			}
+		}
+		return movegen.Move{}, fmt.Errorf("no legal castle matches %q", san)
+	}
+
+	promotion := movegen.Pawn
+	promote := false
+	if i := strings.IndexByte(san, '='); i >= 0 {
+		promote = true
+		promotion = kindFromLetter(san[i+1:])
+		san = san[:i]
+	}
+
+	kind := movegen.Pawn
+	rest := san
+	if i := strings.IndexAny(san[:1], "NBRQK"); i == 0 {
+		kind = kindFromLetter(san[:1])
+		rest = san[1:]
+	}
+	rest = strings.ReplaceAll(rest, "x", "")
+
+	if len(rest) < 2 {
+		return movegen.Move{}, fmt.Errorf("unparseable SAN %q", san)
+	}
+	to, err := parseSquareToken(rest[len(rest)-2:])
+	if err != nil {
+		return movegen.Move{}, err
+	}
+	hint := rest[:len(rest)-2]
+
+	var candidates []movegen.Move
+	for _, m := range movegen.AllLegalMoves(mb, color) {
+		if m.Castle || m.To != to {
+			continue
+		}
+		piece, ok := mb.Squares[m.From]
+		if !ok || piece.Kind != kind {
+			continue
+		}
+		if promote && (!m.Promote || m.Promotion != promotion) {
+			continue
+		}
+		if !hintMatches(hint, m.From) {
+			continue
+		}
+		candidates = append(candidates, m)
+	}
+	switch len(candidates) {
+	case 1:
+		return candidates[0], nil
+	case 0:
+		return movegen.Move{}, fmt.Errorf("no legal move matches %q", san)
+	default:
+		return movegen.Move{}, fmt.Errorf("ambiguous SAN %q", san)
+	}
+}
+
+// hintMatches reports whether from satisfies a SAN disambiguation hint,
+// which may be empty, a file letter, a rank digit, or both.
+func hintMatches(hint string, from movegen.Square) bool {
+	switch len(hint) {
+	case 0:
+		return true
+	case 1:
+		if hint[0] >= '1' && hint[0] <= '8' {
+			return rankDigit(from.Y) == hint
+		}
+		return fileLetter(from.X) == hint
+	default:
+		return squareString(from) == hint
+	}
+}
+
+func kindFromLetter(s string) movegen.Kind {
+	switch s {
+	case "N":
+		return movegen.Knight
+	case "B":
+		return movegen.Bishop
+	case "R":
+		return movegen.Rook
+	case "Q":
+		return movegen.Queen
+	case "K":
+		return movegen.King
+	default:
+		return movegen.Pawn
+	}
+}
+
+func parseSquareToken(s string) (movegen.Square, error) {
+	if len(s) != 2 || s[0] < 'a' || s[0] > 'h' || s[1] < '1' || s[1] > '8' {
+		return movegen.Square{}, fmt.Errorf("invalid square %q", s)
+	}
+	return movegen.Square{X: int(s[0] - 'a'), Y: int(s[1] - '1')}, nil
+}
@@ -0,0 +1,69 @@
+package pgn
+
+import (
+	"testing"
+
+	"chess/board"
+)
+
+func TestReplayAll(t *testing.T) {
+	g := board.NewGame()
+	defer g.Close()
+
+	r := NewPGNReader(g)
+	if err := r.ReplayAll("1. e4 e5 2. Nf3 Nc6 *"); err != nil {
+		t.Fatalf("ReplayAll: %v", err)
+	}
+
+	const want = "r1bqkbnr/pppp1ppp/2n5/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R w KQkq - 2 3"
+	if got := g.FEN(); got != want {
+		t.Errorf("FEN() = %q, want %q", got, want)
+	}
+}
+
+func TestSANDisambiguatesByFile(t *testing.T) {
+	g := board.NewEmptyGame()
+	defer g.Close()
+
+	const fen = "4k3/8/8/8/8/2N3N1/8/4K3 w - - 0 1"
+	if err := g.LoadFEN(fen); err != nil {
+		t.Fatalf("LoadFEN(%q): %v", fen, err)
+	}
+
+	events := make(chan board.MoveEvent, 1)
+	g.Subscribe(events)
+	if err := g.Move("C3", "E2", 0); err != nil {
+		t.Fatalf("Move(C3, E2): %v", err)
+	}
+
+	san, err := SAN(<-events)
+	if err != nil {
+		t.Fatalf("SAN: %v", err)
+	}
+	if san != "Nce2" {
+		t.Errorf("SAN() = %q, want %q", san, "Nce2")
+	}
+}
+
+func TestRecorderFinishWaitsForLastMove(t *testing.T) {
+	g := board.NewGame()
+	defer g.Close()
+
+	events := make(chan board.MoveEvent)
+	g.Subscribe(events)
+
+	r := NewRecorder(DefaultTags())
+	go r.Watch(events)
+
+	for _, mv := range [][2]string{{"E2", "E4"}, {"E7", "E5"}, {"G1", "F3"}, {"B8", "C6"}} {
+		if err := g.Move(mv[0], mv[1], 0); err != nil {
+			t.Fatalf("Move(%s, %s): %v", mv[0], mv[1], err)
+		}
+	}
+	close(events)
+
+	want := Render(DefaultTags(), []string{"e4", "e5", "Nf3", "Nc6"})
+	if got := r.Finish("*"); got != want {
+		t.Errorf("Finish() = %q, want %q", got, want)
+	}
+}